@@ -0,0 +1,150 @@
+package hashmap
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPutAllAndGetAll(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+	m.Put("existing", 0)
+
+	m.PutAll(map[string]int{"existing": 1, "a": 2, "b": 3})
+
+	got := m.GetAll([]string{"existing", "a", "b", "missing"})
+	want := map[string]int{"existing": 1, "a": 2, "b": 3}
+	if !intMapsEqual(got, want) {
+		t.Fatalf("GetAll() = %v, want %v", got, want)
+	}
+	if _, ok := got["missing"]; ok {
+		t.Fatalf("GetAll() returned an entry for a key that was never put")
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+	m.PutAll(map[string]int{"a": 1, "b": 2, "c": 3})
+
+	m.DeleteAll([]string{"a", "c", "never-existed"})
+
+	if m.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", m.Len())
+	}
+	if _, ok := m.Get("b"); !ok {
+		t.Fatalf("Get(%q) = false, want true (should not have been deleted)", "b")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := m.Get(key); ok {
+			t.Fatalf("Get(%q) = true, want false (should have been deleted)", key)
+		}
+	}
+}
+
+func TestPutIfAbsent(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+
+	if !m.PutIfAbsent("a", 1) {
+		t.Fatalf("PutIfAbsent on a missing key = false, want true")
+	}
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(%q) = (%d, %v), want (1, true)", "a", v, ok)
+	}
+
+	if m.PutIfAbsent("a", 2) {
+		t.Fatalf("PutIfAbsent on an existing key = true, want false")
+	}
+	if v, _ := m.Get("a"); v != 1 {
+		t.Fatalf("Get(%q) = %d after a rejected PutIfAbsent, want unchanged value 1", "a", v)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+
+	if old, replaced := m.Replace("missing", 1); replaced || old != 0 {
+		t.Fatalf("Replace on a missing key = (%d, %v), want (0, false)", old, replaced)
+	}
+
+	m.Put("a", 1)
+	old, replaced := m.Replace("a", 2)
+	if !replaced || old != 1 {
+		t.Fatalf("Replace on an existing key = (%d, %v), want (1, true)", old, replaced)
+	}
+	if v, _ := m.Get("a"); v != 2 {
+		t.Fatalf("Get(%q) = %d after Replace, want 2", "a", v)
+	}
+}
+
+// TestGetOrSetComputesOnlyWhenMissing checks the "fn runs under the write
+// lock only when the key is absent" contract: fn must not run at all for an
+// existing key, and must run exactly once when inserting a new one.
+func TestGetOrSetComputesOnlyWhenMissing(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+	m.Put("existing", 1)
+
+	var calls int
+	got := m.GetOrSet("existing", func() int {
+		calls++
+		return 99
+	})
+	if got != 1 {
+		t.Fatalf("GetOrSet on an existing key = %d, want 1", got)
+	}
+	if calls != 0 {
+		t.Fatalf("fn called %d times for an existing key, want 0", calls)
+	}
+
+	got = m.GetOrSet("new", func() int {
+		calls++
+		return 42
+	})
+	if got != 42 {
+		t.Fatalf("GetOrSet on a missing key = %d, want 42", got)
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times for a missing key, want 1", calls)
+	}
+	if v, ok := m.Get("new"); !ok || v != 42 {
+		t.Fatalf("Get(%q) = (%d, %v), want (42, true) after GetOrSet stored it", "new", v, ok)
+	}
+}
+
+// TestMapCompareAndSwapConcurrent has many goroutines race to bump a shared
+// counter via CompareAndSwap; the final value must equal the number of
+// successful swaps, proving no lost updates.
+func TestMapCompareAndSwapConcurrent(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+	m.Put("counter", 0)
+
+	const goroutines = 50
+	const incrementsPerGoroutine = 200
+
+	var successes int64
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < incrementsPerGoroutine; i++ {
+				for {
+					old, _ := m.Get("counter")
+					if m.CompareAndSwap("counter", old, old+1) {
+						atomic.AddInt64(&successes, 1)
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, _ := m.Get("counter")
+	want := int(successes)
+	if got != want {
+		t.Fatalf("counter = %d, want %d (successful CompareAndSwap calls)", got, want)
+	}
+	if want != goroutines*incrementsPerGoroutine {
+		t.Fatalf("successes = %d, want %d", want, goroutines*incrementsPerGoroutine)
+	}
+}
@@ -1,18 +1,49 @@
 package hashmap
 
 import (
-	"sync"
+	"encoding/json"
 	"hash"
 	"hash/crc64"
+	"sync"
 )
 
-// HashMap is a thread safe string, string map
+// HashMap is a thread safe string, string map. It is a thin wrapper around
+// Map[string, string] kept around so existing callers don't have to spell
+// out the type parameters.
+//
+// The zero value embeds a nil *Map and is not ready to use through Put/Get
+// directly; construct one with New or NewHashMap. UnmarshalJSON is the
+// one exception, since decoding into a zero-value HashMap embedded in a
+// larger config struct is a common pattern: it lazily initializes the
+// backing Map with the default crc64 hash function if it hasn't been
+// constructed yet.
 type HashMap struct {
-	nodes []*node
-	hasher sync.Pool
-	size uint64
+	*Map[string, string]
+}
 
-	rwMutex sync.RWMutex
+// adaptHash64 turns a hash.Hash64 factory into the func(string) uint64 shape
+// Map expects, pooling the hashers so concurrent callers don't allocate one
+// per call
+func adaptHash64(hashFn func() hash.Hash64) func(string) uint64 {
+	hasher := sync.Pool{
+		New: func() interface{} {
+			return hashFn()
+		},
+	}
+	return func(key string) uint64 {
+		h := hasher.Get().(hash.Hash64)
+		h.Reset()
+		h.Write([]byte(key))
+		sum := h.Sum64()
+		hasher.Put(h)
+		return sum
+	}
+}
+
+// defaultHashFn returns the crc64-backed func(string) uint64 used by
+// NewSharded and to lazily construct a zero-value HashMap's backing Map
+func defaultHashFn() func(string) uint64 {
+	return adaptHash64(func() hash.Hash64 { return crc64.New(crc64.MakeTable(crc64.ECMA)) })
 }
 
 // New creates a new HashMap that uses crc64 as hash function. Size contains the size of the lookup table
@@ -22,77 +53,59 @@ func New(size uint64) *HashMap {
 
 // NewHashMap lets you define what hash func to use and how big shall the lookup table be
 func NewHashMap(hashFn func() hash.Hash64, size uint64) *HashMap {
-	hm := &HashMap{
-		nodes: make([]*node, size),
-		hasher: sync.Pool{
-			New:func() interface{} {
-				return hashFn()
-			},
-		},
-
-		size: size,
-	}
-	for i := range hm.nodes {
-		hm.nodes[i] = &node{}
-	}
-	return hm
+	return &HashMap{Map: NewMap[string, string](adaptHash64(hashFn), size)}
 }
 
-// getIndex returns the index of the key in the lookup table
-func (hm *HashMap) getIndex(key string) uint64 {
-	hasher := hm.hasher.Get().(hash.Hash64)
-	hasher.Reset()
-	hasher.Write([]byte(key))
-	index := hasher.Sum64() % hm.size
-	hm.hasher.Put(hasher)
-	return index
+// NewSharded creates a ShardedMap[string, string] that uses crc64 as hash
+// function, partitioned across shards stripes so writers to different
+// shards proceed in parallel. size is the initial lookup table size of each
+// shard.
+func NewSharded(shards, size uint64) *ShardedMap[string, string] {
+	return NewShardedMap[string, string](defaultHashFn(), shards, size)
 }
 
-// Put puts a new value in the map. It overwrites values of existing keys
-func (hm *HashMap) Put(key, value string) {
-	index := hm.getIndex(key)
-	hm.rwMutex.Lock()
-	defer hm.rwMutex.Unlock()
-	has, _ := hm.getNode(key)
-	if has != nil {
-		has.value = value
-		return
+// NewFromMap creates a new HashMap pre-populated with the contents of src
+func NewFromMap(src map[string]string) *HashMap {
+	hm := New(uint64(len(src)))
+	for k, v := range src {
+		hm.Put(k, v)
 	}
-	hm.nodes[index].append(&node{key: key, value:value})
+	return hm
 }
 
-// getNode returns the node that contains the element and it's parent or nil
-func (hm *HashMap) getNode(key string) (*node, *node) {
-	index := hm.getIndex(key)
-	elem := hm.nodes[index]
-	for elem.next != nil {
-		parent := elem
-		elem = elem.next
-		if elem.key == key {
-			return elem, parent
-		}
-	}
-	return nil, nil
+// ToMap returns a snapshot of the HashMap's contents as a plain
+// map[string]string
+func (hm *HashMap) ToMap() map[string]string {
+	out := make(map[string]string, hm.Len())
+	hm.Range(func(key, value string) bool {
+		out[key] = value
+		return true
+	})
+	return out
 }
 
-// Get returns the value for a given key and true, or "" and false if the key is not in the hash map
-func (hm *HashMap) Get(key string) (string, bool) {
-	hm.rwMutex.RLock()
-	defer hm.rwMutex.RUnlock()
-	n, _ := hm.getNode(key)
-	if n == nil {
-		return "", false
-	}
-	return n.value, true
+// MarshalJSON implements json.Marshaler, encoding the map's contents as a
+// JSON object
+func (hm *HashMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hm.ToMap())
 }
 
-// Delete deletes the value associated with the key, if it exists
-func (hm *HashMap) Delete(key string) {
-	hm.rwMutex.Lock()
-	defer hm.rwMutex.Unlock()
-	n, parent := hm.getNode(key)
-	if n == nil {
-		return
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents
+// with the decoded JSON object. It lazily constructs the backing Map if hm
+// is a zero value, so decoding into an embedded, not-yet-constructed
+// HashMap field (the common config-loading case) works without panicking.
+func (hm *HashMap) UnmarshalJSON(data []byte) error {
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if hm.Map == nil {
+		hm.Map = NewMap[string, string](defaultHashFn(), uint64(len(raw)))
+	} else {
+		hm.Clear()
+	}
+	for k, v := range raw {
+		hm.Put(k, v)
 	}
-	parent.next = n.next
+	return nil
 }
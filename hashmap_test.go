@@ -0,0 +1,80 @@
+package hashmap
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+func TestHashMapMarshalUnmarshalRoundTrip(t *testing.T) {
+	src := map[string]string{"a": "1", "b": "2", "c": "3"}
+	hm := NewFromMap(src)
+
+	data, err := json.Marshal(hm)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var out HashMap
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got := out.ToMap(); !mapsEqual(got, src) {
+		t.Fatalf("ToMap() = %v, want %v", got, src)
+	}
+}
+
+func TestHashMapUnmarshalZeroValue(t *testing.T) {
+	var hm HashMap
+	if err := json.Unmarshal([]byte(`{"x":"y"}`), &hm); err != nil {
+		t.Fatalf("Unmarshal into zero-value HashMap: %v", err)
+	}
+	if v, ok := hm.Get("x"); !ok || v != "y" {
+		t.Fatalf("Get(%q) = (%q, %v), want (%q, true)", "x", v, ok, "y")
+	}
+}
+
+func TestHashMapUnmarshalReplacesExistingContents(t *testing.T) {
+	hm := NewFromMap(map[string]string{"old": "value"})
+	if err := json.Unmarshal([]byte(`{"new":"value"}`), hm); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if _, ok := hm.Get("old"); ok {
+		t.Fatalf("old key survived UnmarshalJSON, contents should have been replaced")
+	}
+	if v, ok := hm.Get("new"); !ok || v != "value" {
+		t.Fatalf("Get(%q) = (%q, %v), want (%q, true)", "new", v, ok, "value")
+	}
+}
+
+func TestNewFromMapAndToMap(t *testing.T) {
+	src := map[string]string{"foo": "bar", "baz": "qux"}
+	hm := NewFromMap(src)
+
+	if hm.Len() != len(src) {
+		t.Fatalf("Len() = %d, want %d", hm.Len(), len(src))
+	}
+	if got := hm.ToMap(); !mapsEqual(got, src) {
+		t.Fatalf("ToMap() = %v, want %v", got, src)
+	}
+
+	keys := hm.Keys()
+	sort.Strings(keys)
+	want := []string{"baz", "foo"}
+	if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] {
+		t.Fatalf("Keys() = %v, want %v", keys, want)
+	}
+}
+
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
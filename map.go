@@ -0,0 +1,414 @@
+package hashmap
+
+import (
+	"reflect"
+	"sync"
+)
+
+// defaultLoadFactor is the percentage of the table that may be filled before
+// a resize is triggered
+const defaultLoadFactor = 90
+
+// entry is a single key/value slot in a Map's flat backing array
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// Map is a thread safe Robin Hood open-addressing hash map parameterized
+// over comparable keys K and arbitrary values V. Keys are hashed with a
+// user-supplied hashFn so callers can plug in whatever hashing strategy fits
+// their key type, without being forced to box values into interface{}. The
+// table grows automatically as it fills up.
+type Map[K comparable, V any] struct {
+	hashes []uint64
+	elems  []entry[K, V]
+	mask   uint64
+	n      uint64
+	hashFn func(K) uint64
+
+	rwMutex sync.RWMutex
+}
+
+// NewMap lets you define what hash function to use for K and how big the
+// initial lookup table shall be. size is rounded up to the next power of
+// two; the table grows automatically once it gets too full.
+func NewMap[K comparable, V any](hashFn func(K) uint64, size uint64) *Map[K, V] {
+	cap := nextPowerOfTwo(size)
+	return &Map[K, V]{
+		hashes: make([]uint64, cap),
+		elems:  make([]entry[K, V], cap),
+		mask:   cap - 1,
+		hashFn: hashFn,
+	}
+}
+
+// nextPowerOfTwo rounds v up to the next power of two, with a floor of 2 so
+// there is always at least one free slot to terminate probing
+func nextPowerOfTwo(v uint64) uint64 {
+	if v < 2 {
+		return 2
+	}
+	v--
+	v |= v >> 1
+	v |= v >> 2
+	v |= v >> 4
+	v |= v >> 8
+	v |= v >> 16
+	v |= v >> 32
+	return v + 1
+}
+
+// hashKey hashes key with hashFn, forcing the reserved sentinel value 0 to 1
+// so that 0 can be used to mark empty slots
+func (m *Map[K, V]) hashKey(key K) uint64 {
+	h := m.hashFn(key)
+	if h == 0 {
+		h = 1
+	}
+	return h
+}
+
+// probeDistance returns how far an element stored at pos is from its ideal
+// slot for hash
+func probeDistance(hash, pos, mask uint64) uint64 {
+	return (pos - (hash & mask)) & mask
+}
+
+// threshold returns the number of elements the table may hold before it
+// needs to grow
+func (m *Map[K, V]) threshold() uint64 {
+	return uint64(len(m.hashes)) * defaultLoadFactor / 100
+}
+
+// Put puts a new value in the map. It overwrites values of existing keys
+func (m *Map[K, V]) Put(key K, value V) {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	if m.n >= m.threshold() {
+		m.grow()
+	}
+	m.insert(m.hashKey(key), entry[K, V]{key: key, value: value})
+}
+
+// insert places e into the table using Robin Hood displacement, overwriting
+// the value in place if its key is already present. Callers must hold
+// rwMutex for writing.
+func (m *Map[K, V]) insert(hash uint64, e entry[K, V]) {
+	pos := hash & m.mask
+	dist := uint64(0)
+	for {
+		existingHash := m.hashes[pos]
+		if existingHash == 0 {
+			m.hashes[pos] = hash
+			m.elems[pos] = e
+			m.n++
+			return
+		}
+		if existingHash == hash && m.elems[pos].key == e.key {
+			m.elems[pos].value = e.value
+			return
+		}
+		if existingDist := probeDistance(existingHash, pos, m.mask); existingDist < dist {
+			hash, m.hashes[pos] = m.hashes[pos], hash
+			e, m.elems[pos] = m.elems[pos], e
+			dist = existingDist
+		}
+		pos = (pos + 1) & m.mask
+		dist++
+	}
+}
+
+// grow doubles the size of the table and re-inserts every existing element.
+// Callers must hold rwMutex for writing.
+func (m *Map[K, V]) grow() {
+	oldHashes, oldElems := m.hashes, m.elems
+	newCap := uint64(len(m.hashes)) * 2
+	m.hashes = make([]uint64, newCap)
+	m.elems = make([]entry[K, V], newCap)
+	m.mask = newCap - 1
+	m.n = 0
+	for i, h := range oldHashes {
+		if h != 0 {
+			m.insert(h, oldElems[i])
+		}
+	}
+}
+
+// find returns the slot index holding key and true, or false if key is not
+// present. Callers must hold rwMutex.
+func (m *Map[K, V]) find(key K) (uint64, bool) {
+	hash := m.hashKey(key)
+	pos := hash & m.mask
+	dist := uint64(0)
+	for {
+		existingHash := m.hashes[pos]
+		if existingHash == 0 {
+			return 0, false
+		}
+		if existingHash == hash && m.elems[pos].key == key {
+			return pos, true
+		}
+		if probeDistance(existingHash, pos, m.mask) < dist {
+			return 0, false
+		}
+		pos = (pos + 1) & m.mask
+		dist++
+	}
+}
+
+// Get returns the value for a given key and true, or the zero value and
+// false if the key is not in the map
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	m.rwMutex.RLock()
+	defer m.rwMutex.RUnlock()
+	pos, ok := m.find(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return m.elems[pos].value, true
+}
+
+// Delete deletes the value associated with the key, if it exists
+func (m *Map[K, V]) Delete(key K) {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	m.deleteLocked(key)
+}
+
+// deleteLocked removes key from the table using backward-shift deletion.
+// Callers must hold rwMutex for writing.
+func (m *Map[K, V]) deleteLocked(key K) {
+	pos, ok := m.find(key)
+	if !ok {
+		return
+	}
+	m.deleteSlot(pos)
+}
+
+// deleteSlot empties pos, shifting subsequent entries back while they are
+// not in their ideal slot. Callers must hold rwMutex for writing.
+func (m *Map[K, V]) deleteSlot(pos uint64) {
+	for {
+		next := (pos + 1) & m.mask
+		if m.hashes[next] == 0 || probeDistance(m.hashes[next], next, m.mask) == 0 {
+			break
+		}
+		m.hashes[pos] = m.hashes[next]
+		m.elems[pos] = m.elems[next]
+		pos = next
+	}
+	m.hashes[pos] = 0
+	m.elems[pos] = entry[K, V]{}
+	m.n--
+}
+
+// Len returns the number of elements currently stored in the map
+func (m *Map[K, V]) Len() int {
+	m.rwMutex.RLock()
+	defer m.rwMutex.RUnlock()
+	return int(m.n)
+}
+
+// Keys returns a snapshot of all keys currently stored in the map
+func (m *Map[K, V]) Keys() []K {
+	m.rwMutex.RLock()
+	defer m.rwMutex.RUnlock()
+	keys := make([]K, 0, m.n)
+	for i, h := range m.hashes {
+		if h != 0 {
+			keys = append(keys, m.elems[i].key)
+		}
+	}
+	return keys
+}
+
+// Values returns a snapshot of all values currently stored in the map
+func (m *Map[K, V]) Values() []V {
+	m.rwMutex.RLock()
+	defer m.rwMutex.RUnlock()
+	values := make([]V, 0, m.n)
+	for i, h := range m.hashes {
+		if h != 0 {
+			values = append(values, m.elems[i].value)
+		}
+	}
+	return values
+}
+
+// Range calls fn for every key/value pair in the map, stopping early if fn
+// returns false
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	m.rwMutex.RLock()
+	defer m.rwMutex.RUnlock()
+	for i, h := range m.hashes {
+		if h == 0 {
+			continue
+		}
+		if !fn(m.elems[i].key, m.elems[i].value) {
+			return
+		}
+	}
+}
+
+// Clear removes all elements from the map, keeping the current table
+// capacity
+func (m *Map[K, V]) Clear() {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	for i := range m.hashes {
+		m.hashes[i] = 0
+		m.elems[i] = entry[K, V]{}
+	}
+	m.n = 0
+}
+
+// FilterMap removes every key/value pair for which fn returns false, pruning
+// the map in place under a single write lock acquisition
+func (m *Map[K, V]) FilterMap(fn func(key K, value V) bool) {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	var toRemove []K
+	for i, h := range m.hashes {
+		if h == 0 {
+			continue
+		}
+		if !fn(m.elems[i].key, m.elems[i].value) {
+			toRemove = append(toRemove, m.elems[i].key)
+		}
+	}
+	for _, key := range toRemove {
+		m.deleteLocked(key)
+	}
+}
+
+// Iterator is a concurrency-safe snapshot of a Map's contents, taken at the
+// time Iterator is called. It does not hold the map's lock, so long-running
+// consumers can walk it without blocking readers or writers.
+type Iterator[K comparable, V any] struct {
+	entries []entry[K, V]
+	pos     int
+}
+
+// Iterator returns a snapshot iterator over the map's current contents
+func (m *Map[K, V]) Iterator() *Iterator[K, V] {
+	m.rwMutex.RLock()
+	defer m.rwMutex.RUnlock()
+	snapshot := make([]entry[K, V], 0, m.n)
+	for i, h := range m.hashes {
+		if h != 0 {
+			snapshot = append(snapshot, m.elems[i])
+		}
+	}
+	return &Iterator[K, V]{entries: snapshot}
+}
+
+// Next advances the iterator and returns the next key/value pair. ok is
+// false once the iterator is exhausted.
+func (it *Iterator[K, V]) Next() (key K, value V, ok bool) {
+	if it.pos >= len(it.entries) {
+		return key, value, false
+	}
+	e := it.entries[it.pos]
+	it.pos++
+	return e.key, e.value, true
+}
+
+// PutAll inserts every key/value pair in kvs under a single write lock
+// acquisition
+func (m *Map[K, V]) PutAll(kvs map[K]V) {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	for key, value := range kvs {
+		if m.n >= m.threshold() {
+			m.grow()
+		}
+		m.insert(m.hashKey(key), entry[K, V]{key: key, value: value})
+	}
+}
+
+// GetAll returns the values for the given keys under a single read lock
+// acquisition, omitting any key that is not present
+func (m *Map[K, V]) GetAll(keys []K) map[K]V {
+	m.rwMutex.RLock()
+	defer m.rwMutex.RUnlock()
+	out := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if pos, ok := m.find(key); ok {
+			out[key] = m.elems[pos].value
+		}
+	}
+	return out
+}
+
+// DeleteAll removes every key in keys under a single write lock acquisition
+func (m *Map[K, V]) DeleteAll(keys []K) {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	for _, key := range keys {
+		m.deleteLocked(key)
+	}
+}
+
+// PutIfAbsent inserts value for key only if key is not already present,
+// returning true if the insert happened
+func (m *Map[K, V]) PutIfAbsent(key K, value V) bool {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	if _, ok := m.find(key); ok {
+		return false
+	}
+	if m.n >= m.threshold() {
+		m.grow()
+	}
+	m.insert(m.hashKey(key), entry[K, V]{key: key, value: value})
+	return true
+}
+
+// Replace sets key to value only if key is already present, returning the
+// previous value and true if it replaced something
+func (m *Map[K, V]) Replace(key K, value V) (V, bool) {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	pos, ok := m.find(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	old := m.elems[pos].value
+	m.elems[pos].value = value
+	return old, true
+}
+
+// CompareAndSwap sets key to newValue only if its current value deep-equals
+// old, returning true if the swap happened
+func (m *Map[K, V]) CompareAndSwap(key K, old, newValue V) bool {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	pos, ok := m.find(key)
+	if !ok {
+		return false
+	}
+	if !reflect.DeepEqual(m.elems[pos].value, old) {
+		return false
+	}
+	m.elems[pos].value = newValue
+	return true
+}
+
+// GetOrSet returns the current value for key, or, if absent, computes it via
+// fn under the write lock and stores it before returning it
+func (m *Map[K, V]) GetOrSet(key K, fn func() V) V {
+	m.rwMutex.Lock()
+	defer m.rwMutex.Unlock()
+	if pos, ok := m.find(key); ok {
+		return m.elems[pos].value
+	}
+	value := fn()
+	if m.n >= m.threshold() {
+		m.grow()
+	}
+	m.insert(m.hashKey(key), entry[K, V]{key: key, value: value})
+	return value
+}
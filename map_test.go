@@ -0,0 +1,172 @@
+package hashmap
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func fnvHash(s string) uint64 {
+	const offset64 = 14695981039346656037
+	const prime64 = 1099511628211
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
+
+// TestMapAgainstReference drives Map[string,int] and a plain Go map with the
+// same sequence of Put/Get/Delete operations and checks they never diverge,
+// across enough keys to force several grow()s.
+func TestMapAgainstReference(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 2)
+	ref := make(map[string]int)
+
+	rng := rand.New(rand.NewSource(1))
+	keys := make([]string, 200)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	for i := 0; i < 5000; i++ {
+		key := keys[rng.Intn(len(keys))]
+		switch rng.Intn(3) {
+		case 0, 1:
+			value := rng.Intn(1_000_000)
+			m.Put(key, value)
+			ref[key] = value
+		case 2:
+			m.Delete(key)
+			delete(ref, key)
+		}
+
+		if len(ref) != m.Len() {
+			t.Fatalf("length mismatch after op %d: map=%d ref=%d", i, m.Len(), len(ref))
+		}
+	}
+
+	for _, key := range keys {
+		want, wantOk := ref[key]
+		got, gotOk := m.Get(key)
+		if gotOk != wantOk || got != want {
+			t.Fatalf("Get(%q) = (%d, %v), want (%d, %v)", key, got, gotOk, want, wantOk)
+		}
+	}
+}
+
+// TestMapDeleteThenReinsert exercises backward-shift deletion followed by
+// re-insertion into the freed slot.
+func TestMapDeleteThenReinsert(t *testing.T) {
+	m := NewMap[string, string](fnvHash, 4)
+	m.Put("a", "1")
+	m.Put("b", "2")
+	m.Put("c", "3")
+
+	m.Delete("b")
+	if _, ok := m.Get("b"); ok {
+		t.Fatalf("Get(%q) should report deleted key as absent", "b")
+	}
+	if v, ok := m.Get("a"); !ok || v != "1" {
+		t.Fatalf("Get(%q) = (%q, %v), want (%q, true)", "a", v, ok, "1")
+	}
+	if v, ok := m.Get("c"); !ok || v != "3" {
+		t.Fatalf("Get(%q) = (%q, %v), want (%q, true)", "c", v, ok, "3")
+	}
+
+	m.Put("b", "4")
+	if v, ok := m.Get("b"); !ok || v != "4" {
+		t.Fatalf("Get(%q) after reinsert = (%q, %v), want (%q, true)", "b", v, ok, "4")
+	}
+	if m.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", m.Len())
+	}
+}
+
+func TestMapGrowPreservesAllEntries(t *testing.T) {
+	m := NewMap[int, int](func(k int) uint64 { return uint64(k) }, 2)
+	const n = 500
+	for i := 0; i < n; i++ {
+		m.Put(i, i*i)
+	}
+	if m.Len() != n {
+		t.Fatalf("Len() = %d, want %d", m.Len(), n)
+	}
+	for i := 0; i < n; i++ {
+		v, ok := m.Get(i)
+		if !ok || v != i*i {
+			t.Fatalf("Get(%d) = (%d, %v), want (%d, true)", i, v, ok, i*i)
+		}
+	}
+}
+
+// TestIteratorIsSnapshotIsolated checks that mutating the map after taking
+// an Iterator does not affect what the iterator yields.
+func TestIteratorIsSnapshotIsolated(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+	m.Put("a", 1)
+	m.Put("b", 2)
+
+	it := m.Iterator()
+
+	m.Put("c", 3)
+	m.Delete("a")
+	m.Put("b", 20)
+
+	got := make(map[string]int)
+	for {
+		k, v, ok := it.Next()
+		if !ok {
+			break
+		}
+		got[k] = v
+	}
+
+	want := map[string]int{"a": 1, "b": 2}
+	if !intMapsEqual(got, want) {
+		t.Fatalf("Iterator snapshot = %v, want %v (taken before later mutations)", got, want)
+	}
+
+	if _, _, ok := it.Next(); ok {
+		t.Fatalf("Next() returned ok=true after exhausting the iterator")
+	}
+}
+
+func TestFilterMapPrunesInPlace(t *testing.T) {
+	m := NewMap[string, int](fnvHash, 4)
+	m.Put("even-2", 2)
+	m.Put("odd-1", 1)
+	m.Put("even-4", 4)
+	m.Put("odd-3", 3)
+
+	m.FilterMap(func(_ string, v int) bool {
+		return v%2 == 0
+	})
+
+	if m.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", m.Len())
+	}
+	for _, key := range []string{"even-2", "even-4"} {
+		if _, ok := m.Get(key); !ok {
+			t.Fatalf("Get(%q) = false, want true (should have survived the filter)", key)
+		}
+	}
+	for _, key := range []string{"odd-1", "odd-3"} {
+		if _, ok := m.Get(key); ok {
+			t.Fatalf("Get(%q) = true, want false (should have been pruned)", key)
+		}
+	}
+}
+
+func intMapsEqual(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package hashmap
+
+import (
+	"math/bits"
+	"runtime"
+)
+
+// ShardedMap is a thread safe map that partitions keys across several
+// independent Map shards so that writers to different shards never contend
+// on the same lock, at the cost of Len/Keys/Values/Range having to visit
+// every shard.
+type ShardedMap[K comparable, V any] struct {
+	shards    []*Map[K, V]
+	mask      uint64
+	shardBits uint
+	hashFn    func(K) uint64
+}
+
+// NewShardedMap creates a ShardedMap with shardCount shards, each with its
+// own lookup table of the given size. shardCount is rounded up to a power of
+// two, with a minimum of 2; a shardCount of 0 defaults to
+// runtime.GOMAXPROCS(0)*8.
+func NewShardedMap[K comparable, V any](hashFn func(K) uint64, shardCount, size uint64) *ShardedMap[K, V] {
+	if shardCount == 0 {
+		shardCount = uint64(runtime.GOMAXPROCS(0) * 8)
+	}
+	shardCount = nextPowerOfTwo(shardCount)
+	mask := shardCount - 1
+	sm := &ShardedMap[K, V]{
+		shards:    make([]*Map[K, V], shardCount),
+		mask:      mask,
+		shardBits: uint(bits.Len64(mask)),
+		hashFn:    hashFn,
+	}
+	for i := range sm.shards {
+		sm.shards[i] = NewMap[K, V](hashFn, size)
+	}
+	return sm
+}
+
+// getShard returns the shard responsible for key. Shard selection uses the
+// high bits of the hash, while Map.insert/find use the low bits (hash&mask)
+// for in-table placement; keeping these disjoint avoids collapsing each
+// shard's Robin Hood table into a plain linear-probe chain whenever a
+// shard's capacity is a bit-subset of the shard count.
+func (sm *ShardedMap[K, V]) getShard(key K) *Map[K, V] {
+	hash := sm.hashFn(key)
+	return sm.shards[(hash>>(64-sm.shardBits))&sm.mask]
+}
+
+// Put puts a new value in the map. It overwrites values of existing keys
+func (sm *ShardedMap[K, V]) Put(key K, value V) {
+	sm.getShard(key).Put(key, value)
+}
+
+// Get returns the value for a given key and true, or the zero value and
+// false if the key is not in the map
+func (sm *ShardedMap[K, V]) Get(key K) (V, bool) {
+	return sm.getShard(key).Get(key)
+}
+
+// Delete deletes the value associated with the key, if it exists
+func (sm *ShardedMap[K, V]) Delete(key K) {
+	sm.getShard(key).Delete(key)
+}
+
+// Len returns the total number of elements stored across all shards
+func (sm *ShardedMap[K, V]) Len() int {
+	total := 0
+	for _, s := range sm.shards {
+		total += s.Len()
+	}
+	return total
+}
+
+// Keys returns a snapshot of all keys currently stored across all shards
+func (sm *ShardedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, sm.Len())
+	for _, s := range sm.shards {
+		keys = append(keys, s.Keys()...)
+	}
+	return keys
+}
+
+// Values returns a snapshot of all values currently stored across all shards
+func (sm *ShardedMap[K, V]) Values() []V {
+	values := make([]V, 0, sm.Len())
+	for _, s := range sm.shards {
+		values = append(values, s.Values()...)
+	}
+	return values
+}
+
+// Range calls fn for every key/value pair across all shards, stopping early
+// if fn returns false
+func (sm *ShardedMap[K, V]) Range(fn func(key K, value V) bool) {
+	for _, s := range sm.shards {
+		done := false
+		s.Range(func(key K, value V) bool {
+			if !fn(key, value) {
+				done = true
+				return false
+			}
+			return true
+		})
+		if done {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package hashmap
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestShardedMapDistributesAcrossShards guards against shard selection and
+// in-shard slot placement reusing the same low bits of the hash, which
+// would collapse every key routed to a shard into a single linear probe
+// chain starting at slot 0.
+func TestShardedMapDistributesAcrossShards(t *testing.T) {
+	sm := NewShardedMap[string, int](fnvHash, 64, 16)
+
+	occupied := make(map[uint64]bool)
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		shard := sm.getShard(key)
+		shard.Put(key, i)
+		pos, ok := shard.find(key)
+		if !ok {
+			t.Fatalf("find(%q) failed right after Put", key)
+		}
+		occupied[pos] = true
+	}
+
+	if len(occupied) < 5 {
+		t.Fatalf("keys clustered into only %d distinct slots, want a spread across the table", len(occupied))
+	}
+}
+
+// TestShardedMapConcurrentPutGetDelete exercises Put/Get/Delete from many
+// goroutines concurrently. Run with -race to catch any missing locking.
+func TestShardedMapConcurrentPutGetDelete(t *testing.T) {
+	sm := NewShardedMap[int, int](func(k int) uint64 { return uint64(k) }, 16, 8)
+
+	const goroutines = 32
+	const opsPerGoroutine = 2000
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < opsPerGoroutine; i++ {
+				key := (g*opsPerGoroutine + i) % 500
+				switch i % 3 {
+				case 0:
+					sm.Put(key, key)
+				case 1:
+					sm.Get(key)
+				case 2:
+					sm.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}